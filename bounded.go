@@ -0,0 +1,357 @@
+package cache
+
+import (
+	"container/heap"
+	"container/list"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// boundedEntry is the value stored in both the LRU list and the entries map.
+type boundedEntry struct {
+	key  string
+	item *Item
+	cost int64
+}
+
+// expirationQueue is a min-heap of live keys ordered by Item.Expiration, used
+// by the janitor goroutine to find the next key due to expire without
+// scanning every entry.
+type expirationQueue []*expirationEntry
+
+type expirationEntry struct {
+	key        string
+	expiration time.Time
+	index      int
+}
+
+func (me expirationQueue) Len() int { return len(me) }
+
+func (me expirationQueue) Less(i, j int) bool {
+	return me[i].expiration.Before(me[j].expiration)
+}
+
+func (me expirationQueue) Swap(i, j int) {
+	me[i], me[j] = me[j], me[i]
+	me[i].index = i
+	me[j].index = j
+}
+
+func (me *expirationQueue) Push(x interface{}) {
+	entry := x.(*expirationEntry)
+	entry.index = len(*me)
+	*me = append(*me, entry)
+}
+
+func (me *expirationQueue) Pop() interface{} {
+	old := *me
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	entry.index = -1
+	*me = old[:n-1]
+	return entry
+}
+
+// boundedCache is a Cache bounded to a maximum entry count via LRU eviction.
+// Unlike simpleCache/lockedCache/coalescedCache, it does not rely solely on
+// lazy expiration checks in Get: a background janitor goroutine actively
+// evicts expired entries as they come due.
+//
+// capacity is a cost budget rather than a strict entry count: entries Set
+// without WithCost default to a cost of 1, so capacity behaves like a
+// maximum entry count unless callers opt into cost-based eviction.
+type boundedCache struct {
+	store Adapter
+
+	ttl      time.Duration
+	capacity int
+
+	mu        sync.Mutex
+	entries   map[string]*list.Element
+	lru       *list.List
+	totalCost int64
+
+	expirations expirationQueue
+	expiryIndex map[string]*expirationEntry
+
+	janitorTick time.Duration
+	wake        chan struct{}
+	stop        chan struct{}
+	stopped     sync.Once
+
+	// singleflight coalesces concurrent misses for the same key so only
+	// one goroutine ever calls store.Query and setItem for it; without
+	// this, Get's miss path releases mu before fetching, letting
+	// concurrent misses race each other into setItem.
+	singleflight singleflight.Group
+
+	metrics Metrics
+	hooks   Hooks
+}
+
+// NewBoundedCache returns an instance of boundedCache whose janitor
+// goroutine is already running. Callers must call Stop() when done with it
+// to avoid leaking the goroutine.
+func NewBoundedCache(store Adapter, ttl time.Duration, capacity int, opts ...CacheOption) *boundedCache {
+	cfg := newCacheConfig(opts...)
+
+	me := &boundedCache{
+		store:       store,
+		ttl:         ttl,
+		capacity:    capacity,
+		entries:     make(map[string]*list.Element),
+		lru:         list.New(),
+		expiryIndex: make(map[string]*expirationEntry),
+		janitorTick: cfg.janitorTick,
+		wake:        make(chan struct{}, 1),
+		stop:        make(chan struct{}),
+		metrics:     cfg.metrics,
+		hooks:       cfg.hooks,
+	}
+
+	go me.janitor()
+
+	return me
+}
+
+func (me *boundedCache) fetchAndStore(key string, opts callOptions) (*Item, error) {
+	start := time.Now()
+	value, err := query(me.store, key, opts)
+	me.metrics.ObserveLoadDuration(time.Since(start))
+	if err != nil {
+		me.metrics.IncLoaderErrors()
+		return nil, err
+	}
+
+	// Copy rather than store the Adapter's returned *Item directly:
+	// setItem mutates Expiration in place, and an Adapter is free to
+	// return the same backing object on repeated calls.
+	stored := *value.(*Item)
+	item := &stored
+	me.setItem(key, item, opts)
+	fireInsert(me.hooks, key, item)
+	return item, nil
+}
+
+// fetchAndStoreOnce coalesces concurrent fetchAndStore calls for key into
+// one store.Query, so a miss or expiration doesn't let several goroutines
+// race each other into setItem.
+func (me *boundedCache) fetchAndStoreOnce(key string, opts callOptions) (*Item, error) {
+	value, err, _ := me.singleflight.Do(key, func() (interface{}, error) {
+		return me.fetchAndStore(key, opts)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return value.(*Item), nil
+}
+
+func (me *boundedCache) Get(key string, opts ...Option) (*Item, error) {
+	o := newCallOptions(opts...)
+
+	me.mu.Lock()
+	elem, ok := me.entries[key]
+	if !ok {
+		me.mu.Unlock()
+		me.metrics.IncMisses()
+		return me.fetchAndStoreOnce(key, o)
+	}
+
+	entry := elem.Value.(*boundedEntry)
+	if entry.item.Expired(time.Now()) {
+		stale := entry.item
+		me.removeLocked(key)
+		me.mu.Unlock()
+
+		me.metrics.IncMisses()
+		fireExpire(me.hooks, key, stale)
+
+		fetched, err := me.fetchAndStoreOnce(key, o)
+		if err != nil {
+			return staleIfOK(stale, o, err)
+		}
+		return fetched, nil
+	}
+
+	me.lru.MoveToFront(elem)
+	item := entry.item
+	me.mu.Unlock()
+
+	me.metrics.IncHits()
+	return item, nil
+}
+
+func (me *boundedCache) Set(key string, item *Item, opts ...Option) {
+	me.setItem(key, item, newCallOptions(opts...))
+}
+
+func (me *boundedCache) Delete(key string) error {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+
+	me.removeLocked(key)
+	return nil
+}
+
+func (me *boundedCache) setItem(key string, item *Item, opts callOptions) {
+	ttl := me.ttl
+	if opts.ttlSet {
+		ttl = opts.ttl
+	}
+
+	if ttl > 0 {
+		item.Expiration = time.Now().Add(ttl)
+	}
+
+	me.mu.Lock()
+	defer me.mu.Unlock()
+
+	if elem, ok := me.entries[key]; ok {
+		existing := elem.Value.(*boundedEntry)
+		me.totalCost += opts.cost - existing.cost
+		existing.item = item
+		existing.cost = opts.cost
+		me.lru.MoveToFront(elem)
+	} else {
+		elem := me.lru.PushFront(&boundedEntry{key: key, item: item, cost: opts.cost})
+		me.entries[key] = elem
+		me.totalCost += opts.cost
+	}
+
+	for me.capacity > 0 && me.totalCost > int64(me.capacity) && me.lru.Len() > 0 {
+		me.evictOldestLocked()
+	}
+
+	me.trackExpirationLocked(key, item.Expiration)
+	me.wakeJanitor()
+}
+
+// Len returns the number of entries currently held.
+func (me *boundedCache) Len() int {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	return me.lru.Len()
+}
+
+// Cap returns the configured capacity (cost budget). Zero means unbounded.
+func (me *boundedCache) Cap() int {
+	return me.capacity
+}
+
+// Stop shuts down the janitor goroutine. Safe to call more than once.
+func (me *boundedCache) Stop() {
+	me.stopped.Do(func() {
+		close(me.stop)
+	})
+}
+
+func (me *boundedCache) evictOldestLocked() {
+	elem := me.lru.Back()
+	if elem == nil {
+		return
+	}
+
+	entry := elem.Value.(*boundedEntry)
+	me.removeLocked(entry.key)
+	me.metrics.IncEvictions()
+	fireEvict(me.hooks, entry.key, entry.item)
+}
+
+// removeLocked deletes key from the LRU list, the entries map, and the
+// expiration queue. Callers must hold me.mu.
+func (me *boundedCache) removeLocked(key string) {
+	if elem, ok := me.entries[key]; ok {
+		me.totalCost -= elem.Value.(*boundedEntry).cost
+		me.lru.Remove(elem)
+		delete(me.entries, key)
+	}
+
+	if entry, ok := me.expiryIndex[key]; ok {
+		heap.Remove(&me.expirations, entry.index)
+		delete(me.expiryIndex, key)
+	}
+}
+
+// trackExpirationLocked adds or updates key's position in the expiration
+// queue. Callers must hold me.mu.
+func (me *boundedCache) trackExpirationLocked(key string, expiration time.Time) {
+	if expiration.IsZero() {
+		return
+	}
+
+	if entry, ok := me.expiryIndex[key]; ok {
+		entry.expiration = expiration
+		heap.Fix(&me.expirations, entry.index)
+		return
+	}
+
+	entry := &expirationEntry{key: key, expiration: expiration}
+	heap.Push(&me.expirations, entry)
+	me.expiryIndex[key] = entry
+}
+
+func (me *boundedCache) wakeJanitor() {
+	select {
+	case me.wake <- struct{}{}:
+	default:
+	}
+}
+
+// janitor sleeps until the nearest expiration, evicts it, and repeats. It
+// exits once Stop() is called.
+func (me *boundedCache) janitor() {
+	timer := time.NewTimer(me.janitorTick)
+	defer timer.Stop()
+
+	for {
+		me.mu.Lock()
+		var wait time.Duration
+		if me.expirations.Len() == 0 {
+			wait = me.janitorTick
+		} else {
+			wait = time.Until(me.expirations[0].expiration)
+			if wait < 0 {
+				wait = 0
+			}
+		}
+		me.mu.Unlock()
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+
+		select {
+		case <-me.stop:
+			return
+		case <-me.wake:
+			continue
+		case <-timer.C:
+			me.evictExpired()
+		}
+	}
+}
+
+func (me *boundedCache) evictExpired() {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+
+	now := time.Now()
+	for me.expirations.Len() > 0 && !me.expirations[0].expiration.After(now) {
+		entry := me.expirations[0]
+		elem := me.entries[entry.key]
+		item := elem.Value.(*boundedEntry).item
+
+		me.removeLocked(entry.key)
+		me.metrics.IncEvictions()
+		fireExpire(me.hooks, entry.key, item)
+	}
+}
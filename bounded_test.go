@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dkrichards86/gocache/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoundedCache(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockAdapter := mocks.NewMockAdapter(mockCtrl)
+	mockAdapter.EXPECT().Query(testKey).AnyTimes().Return(testElem, nil)
+	testCache := NewBoundedCache(mockAdapter, time.Duration(time.Microsecond), 0)
+	defer testCache.Stop()
+	doCacheTest(t, testCache, 0)
+}
+
+func TestBoundedCache_Concurrent(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockAdapter := mocks.NewMockAdapter(mockCtrl)
+	mockAdapter.EXPECT().Query(testKey).AnyTimes().Return(testElem, nil)
+	testCache := NewBoundedCache(mockAdapter, time.Duration(time.Microsecond), 0)
+	defer testCache.Stop()
+	doCacheTest(t, testCache, 10000)
+}
+
+func TestBoundedCache_EvictsOverCapacity(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockAdapter := mocks.NewMockAdapter(mockCtrl)
+	mockAdapter.EXPECT().Query(gomock.Any()).AnyTimes().Return(testElem, nil)
+	testCache := NewBoundedCache(mockAdapter, time.Duration(0), 2)
+	defer testCache.Stop()
+
+	testCache.Set("a", &Item{Value: "a"})
+	testCache.Set("b", &Item{Value: "b"})
+	testCache.Set("c", &Item{Value: "c"})
+
+	assert.Equal(t, 2, testCache.Len())
+	assert.Equal(t, 2, testCache.Cap())
+
+	_, ok := testCache.entries["a"]
+	assert.False(t, ok, "oldest entry should have been evicted")
+}
+
+func TestBoundedCache_JanitorExpiresEntries(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockAdapter := mocks.NewMockAdapter(mockCtrl)
+	mockAdapter.EXPECT().Query(testKey).AnyTimes().Return(testElem, nil)
+
+	metrics := &fakeMetrics{}
+	testCache := NewBoundedCache(mockAdapter, time.Millisecond, 0, WithJanitorTick(time.Millisecond), WithMetrics(metrics))
+	defer testCache.Stop()
+
+	testCache.Set(testKey, &Item{Value: "hey oh"})
+	assert.Equal(t, 1, testCache.Len())
+
+	assert.Eventually(t, func() bool {
+		return testCache.Len() == 0
+	}, time.Second, time.Millisecond)
+
+	assert.Equal(t, 1, metrics.evictions, "janitor-driven TTL expiry should be counted the same as capacity eviction")
+}
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
@@ -11,6 +12,19 @@ import (
 type Item struct {
 	Value      interface{}
 	Expiration time.Time
+
+	// SoftExpiration and HardExpiration are set by coalescedCache.
+	// SoftExpiration, set only when WithEarlyRefresh is enabled, marks when
+	// the item becomes eligible for background refresh; HardExpiration, set
+	// whenever a TTL applies, marks when it's no longer servable at all.
+	// Unused (zero) by the other caches.
+	SoftExpiration time.Time
+	HardExpiration time.Time
+
+	// Source is the index, within a chainCache's layers, of the layer that
+	// served this Item: 0 is the shallowest layer. Unset (0) outside of a
+	// chainCache.
+	Source int
 }
 
 // Expired returns true if the item has expired.
@@ -24,8 +38,34 @@ func (me *Item) Expired(now time.Time) bool {
 
 // Cache is an interface for our cache
 type Cache interface {
-	Get(key string) (*Item, error)
-	Set(key string, value *Item)
+	Get(key string, opts ...Option) (*Item, error)
+	Set(key string, value *Item, opts ...Option)
+	Delete(key string) error
+}
+
+// query runs the per-call WithLoader override if one was given, otherwise
+// falls back to the cache's own Adapter.
+func query(store Adapter, key string, opts callOptions) (interface{}, error) {
+	if opts.loader != nil {
+		return opts.loader(key)
+	}
+
+	return store.Query(key)
+}
+
+// staleIfOK returns item if a WithStaleOK grace window was set and item
+// hasn't expired past it, so a failing refetch doesn't have to surface an
+// error to the caller. Otherwise it returns fetchErr unchanged.
+func staleIfOK(item *Item, opts callOptions, fetchErr error) (*Item, error) {
+	if opts.staleOK <= 0 || item == nil {
+		return nil, fetchErr
+	}
+
+	if time.Since(item.Expiration) <= opts.staleOK {
+		return item, nil
+	}
+
+	return nil, fetchErr
 }
 
 type simpleCache struct {
@@ -34,45 +74,78 @@ type simpleCache struct {
 	ttl time.Duration
 
 	entries map[string]interface{}
+
+	metrics Metrics
+	hooks   Hooks
 }
 
-func (me *simpleCache) fetchAndStore(key string) (*Item, error) {
-	value, err := me.store.Query(key)
+func (me *simpleCache) fetchAndStore(key string, opts callOptions) (*Item, error) {
+	start := time.Now()
+	value, err := query(me.store, key, opts)
+	me.metrics.ObserveLoadDuration(time.Since(start))
 	if err != nil {
+		me.metrics.IncLoaderErrors()
 		return nil, err
 	}
 
 	item := value.(*Item)
-	me.Set(key, item)
+	me.setItem(key, item, opts)
+	fireInsert(me.hooks, key, item)
 	return item, nil
 }
 
-func (me *simpleCache) Get(key string) (*Item, error) {
+func (me *simpleCache) Get(key string, opts ...Option) (*Item, error) {
+	o := newCallOptions(opts...)
+
 	value, ok := me.entries[key]
 	if !ok {
-		return me.fetchAndStore(key)
+		me.metrics.IncMisses()
+		return me.fetchAndStore(key, o)
 	}
 
 	item := value.(*Item)
 	if item.Expired(time.Now()) {
-		return me.fetchAndStore(key)
+		me.metrics.IncMisses()
+		fireExpire(me.hooks, key, item)
+
+		fetched, err := me.fetchAndStore(key, o)
+		if err != nil {
+			return staleIfOK(item, o, err)
+		}
+		return fetched, nil
 	}
 
+	me.metrics.IncHits()
 	return item, nil
 }
 
-func (me *simpleCache) Set(key string, item *Item) {
-	if me.ttl > 0 {
-		item.Expiration = time.Now().Add(me.ttl)
+func (me *simpleCache) setItem(key string, item *Item, opts callOptions) {
+	ttl := me.ttl
+	if opts.ttlSet {
+		ttl = opts.ttl
+	}
+
+	if ttl > 0 {
+		item.Expiration = time.Now().Add(ttl)
 	}
 
 	me.entries[key] = item
 }
 
+func (me *simpleCache) Set(key string, item *Item, opts ...Option) {
+	me.setItem(key, item, newCallOptions(opts...))
+}
+
+func (me *simpleCache) Delete(key string) error {
+	delete(me.entries, key)
+	return nil
+}
+
 // NewSimpleCache returns an instance of simpleCache
-func NewSimpleCache(store Adapter, ttl time.Duration) *simpleCache {
+func NewSimpleCache(store Adapter, ttl time.Duration, opts ...CacheOption) *simpleCache {
+	cfg := newCacheConfig(opts...)
 	entries := make(map[string]interface{})
-	return &simpleCache{store: store, entries: entries, ttl: ttl}
+	return &simpleCache{store: store, entries: entries, ttl: ttl, metrics: cfg.metrics, hooks: cfg.hooks}
 }
 
 type concurrentCache struct {
@@ -81,45 +154,78 @@ type concurrentCache struct {
 	ttl time.Duration
 
 	entries *sync.Map
+
+	metrics Metrics
+	hooks   Hooks
 }
 
-func (me *concurrentCache) fetchAndStore(key string) (*Item, error) {
-	value, err := me.store.Query(key)
+func (me *concurrentCache) fetchAndStore(key string, opts callOptions) (*Item, error) {
+	start := time.Now()
+	value, err := query(me.store, key, opts)
+	me.metrics.ObserveLoadDuration(time.Since(start))
 	if err != nil {
+		me.metrics.IncLoaderErrors()
 		return nil, err
 	}
 
 	item := value.(*Item)
-	me.Set(key, item)
+	me.setItem(key, item, opts)
+	fireInsert(me.hooks, key, item)
 	return item, nil
 }
 
-func (me *concurrentCache) Get(key string) (*Item, error) {
+func (me *concurrentCache) Get(key string, opts ...Option) (*Item, error) {
+	o := newCallOptions(opts...)
+
 	value, ok := me.entries.Load(key)
 	if !ok {
-		return me.fetchAndStore(key)
+		me.metrics.IncMisses()
+		return me.fetchAndStore(key, o)
 	}
 
 	item := value.(*Item)
 	if item.Expired(time.Now()) {
-		return me.fetchAndStore(key)
+		me.metrics.IncMisses()
+		fireExpire(me.hooks, key, item)
+
+		fetched, err := me.fetchAndStore(key, o)
+		if err != nil {
+			return staleIfOK(item, o, err)
+		}
+		return fetched, nil
 	}
 
+	me.metrics.IncHits()
 	return item, nil
 }
 
-func (me *concurrentCache) Set(key string, item *Item) {
-	if me.ttl > 0 {
-		item.Expiration = time.Now().Add(me.ttl)
+func (me *concurrentCache) setItem(key string, item *Item, opts callOptions) {
+	ttl := me.ttl
+	if opts.ttlSet {
+		ttl = opts.ttl
+	}
+
+	if ttl > 0 {
+		item.Expiration = time.Now().Add(ttl)
 	}
 
 	me.entries.Store(key, item)
 }
 
+func (me *concurrentCache) Set(key string, item *Item, opts ...Option) {
+	me.setItem(key, item, newCallOptions(opts...))
+}
+
+func (me *concurrentCache) Delete(key string) error {
+	me.entries.Delete(key)
+	return nil
+}
+
 // NewConcurrentCache returns an instance of concurrentCache
-func NewConcurrentCache(store Adapter, ttl time.Duration) *concurrentCache {
+func NewConcurrentCache(store Adapter, ttl time.Duration, opts ...CacheOption) *concurrentCache {
+	cfg := newCacheConfig(opts...)
 	entries := &sync.Map{}
-	return &concurrentCache{store: store, entries: entries, ttl: ttl}
+	return &concurrentCache{store: store, entries: entries, ttl: ttl, metrics: cfg.metrics, hooks: cfg.hooks}
 }
 
 // lockedCache
@@ -130,6 +236,11 @@ type lockedCache struct {
 
 	entries sync.Map
 	locks   sync.Map
+
+	metrics  Metrics
+	hooks    Hooks
+	eventBus EventBus
+	origin   string
 }
 
 func (me *lockedCache) getLock(key string) *sync.Mutex {
@@ -137,50 +248,106 @@ func (me *lockedCache) getLock(key string) *sync.Mutex {
 	return rawLock.(*sync.Mutex)
 }
 
-func (me *lockedCache) fetchAndStore(key string) (*Item, error) {
-	value, err := me.store.Query(key)
+func (me *lockedCache) fetchAndStore(key string, opts callOptions) (*Item, error) {
+	start := time.Now()
+	value, err := query(me.store, key, opts)
+	me.metrics.ObserveLoadDuration(time.Since(start))
 	if err != nil {
 		// Check err type here
+		me.metrics.IncLoaderErrors()
 		return nil, err
 	}
 
 	item := value.(*Item)
-	me.Set(key, item)
+	me.setItem(key, item, opts)
+	fireInsert(me.hooks, key, item)
 	return item, nil
 }
 
-func (me *lockedCache) Get(key string) (*Item, error) {
+func (me *lockedCache) Get(key string, opts ...Option) (*Item, error) {
 	lock := me.getLock(key)
 	lock.Lock()
 	defer lock.Unlock()
 
+	o := newCallOptions(opts...)
+
 	value, ok := me.entries.Load(key)
 	if !ok {
-		return me.fetchAndStore(key)
+		me.metrics.IncMisses()
+		return me.fetchAndStore(key, o)
 	}
 
 	item := value.(*Item)
 	if item.Expired(time.Now()) {
-		return me.fetchAndStore(key)
+		me.metrics.IncMisses()
+		fireExpire(me.hooks, key, item)
+
+		fetched, err := me.fetchAndStore(key, o)
+		if err != nil {
+			return staleIfOK(item, o, err)
+		}
+		return fetched, nil
 	}
 
+	me.metrics.IncHits()
 	return item, nil
 }
 
-func (me *lockedCache) Set(key string, item *Item) {
-	if me.ttl > 0 {
-		item.Expiration = time.Now().Add(me.ttl)
+func (me *lockedCache) setItem(key string, item *Item, opts callOptions) {
+	ttl := me.ttl
+	if opts.ttlSet {
+		ttl = opts.ttl
+	}
+
+	if ttl > 0 {
+		item.Expiration = time.Now().Add(ttl)
 	}
 
 	me.entries.Store(key, item)
 }
 
+func (me *lockedCache) Set(key string, item *Item, opts ...Option) {
+	me.setItem(key, item, newCallOptions(opts...))
+	publishInvalidate(me.eventBus, me.origin, key)
+}
+
+func (me *lockedCache) Delete(key string) error {
+	me.entries.Delete(key)
+
+	if me.eventBus == nil {
+		return nil
+	}
+
+	return me.eventBus.Publish(me.origin, key)
+}
+
 // NewLockedCache returns an instance of lockedCache
-func NewLockedCache(store Adapter, ttl time.Duration) *lockedCache {
+func NewLockedCache(store Adapter, ttl time.Duration, opts ...CacheOption) *lockedCache {
+	cfg := newCacheConfig(opts...)
 	var entries sync.Map
 	var locks sync.Map
 
-	return &lockedCache{store: store, entries: entries, locks: locks, ttl: ttl}
+	me := &lockedCache{
+		store:    store,
+		entries:  entries,
+		locks:    locks,
+		ttl:      ttl,
+		metrics:  cfg.metrics,
+		hooks:    cfg.hooks,
+		eventBus: cfg.eventBus,
+	}
+	me.origin = fmt.Sprintf("%p", me)
+
+	if me.eventBus != nil {
+		me.eventBus.Subscribe(func(origin, key string) {
+			if origin == me.origin {
+				return
+			}
+			me.entries.Delete(key)
+		})
+	}
+
+	return me
 }
 
 // coalescedCache
@@ -192,47 +359,187 @@ type coalescedCache struct {
 	entries sync.Map
 
 	singleflight singleflight.Group
+
+	// refreshing tracks keys with a background WithEarlyRefresh refresh in
+	// flight, so concurrent Gets never trigger more than one loader call.
+	refreshing sync.Map
+
+	earlyRefreshThreshold float64
+
+	metrics  Metrics
+	hooks    Hooks
+	eventBus EventBus
+	origin   string
 }
 
-func (me *coalescedCache) fetchAndStore(key string) (*Item, error) {
-	value, err := me.store.Query(key)
+func (me *coalescedCache) fetchAndStore(key string, opts callOptions) (*Item, error) {
+	start := time.Now()
+	value, err := query(me.store, key, opts)
+	me.metrics.ObserveLoadDuration(time.Since(start))
 	if err != nil {
 		// Check err type here
+		me.metrics.IncLoaderErrors()
 		return nil, err
 	}
 
-	item := value.(*Item)
-	me.Set(key, item)
+	// Copy rather than store the Adapter's returned *Item directly:
+	// setItem mutates Expiration/SoftExpiration/HardExpiration in place,
+	// and refreshAsync calls fetchAndStore on a goroutine that runs
+	// concurrently with serveEarlyRefresh reading the item it's
+	// replacing, so the two must never share one object.
+	stored := *value.(*Item)
+	item := &stored
+	me.setItem(key, item, opts)
+	fireInsert(me.hooks, key, item)
 	return item, nil
 }
 
-func (me *coalescedCache) Get(key string) (*Item, error) {
+// refreshAsync kicks off, at most once per key at a time, a background
+// fetchAndStore so a Get serving a soft- or hard-expired item doesn't have
+// to block the caller on store.Query.
+func (me *coalescedCache) refreshAsync(key string, opts callOptions) {
+	if _, inFlight := me.refreshing.LoadOrStore(key, struct{}{}); inFlight {
+		return
+	}
+
+	go func() {
+		defer me.refreshing.Delete(key)
+		me.singleflight.Do(key, func() (interface{}, error) {
+			return me.fetchAndStore(key, opts)
+		})
+	}()
+}
+
+func (me *coalescedCache) Get(key string, opts ...Option) (*Item, error) {
+	o := newCallOptions(opts...)
+
+	if me.earlyRefreshThreshold > 0 {
+		if item, ok := me.entries.Load(key); ok {
+			if stale, served := me.serveEarlyRefresh(key, item.(*Item), o); served {
+				return stale, nil
+			}
+		}
+	}
+
 	value, err, _ := me.singleflight.Do(key, func() (interface{}, error) {
 		item, ok := me.entries.Load(key)
 		if !ok {
-			return me.fetchAndStore(key)
+			me.metrics.IncMisses()
+			return me.fetchAndStore(key, o)
 		}
 
 		if item.(*Item).Expired(time.Now()) {
-			return me.fetchAndStore(key)
+			me.metrics.IncMisses()
+			fireExpire(me.hooks, key, item.(*Item))
+
+			fetched, err := me.fetchAndStore(key, o)
+			if err != nil {
+				return staleIfOK(item.(*Item), o, err)
+			}
+			return fetched, nil
 		}
 
+		me.metrics.IncHits()
 		return item, nil
 	})
 
-	return value.(*Item), err
+	if err != nil {
+		return nil, err
+	}
+
+	return value.(*Item), nil
+}
+
+// serveEarlyRefresh implements stale-while-revalidate: an item past its
+// SoftExpiration is returned as-is while a refresh runs in the background;
+// an item past its HardExpiration is still returned stale, for up to one
+// more TTL, while the same background refresh runs. served is false once
+// the grace window is exhausted, so the caller falls through to a normal
+// blocking fetch.
+func (me *coalescedCache) serveEarlyRefresh(key string, item *Item, opts callOptions) (*Item, bool) {
+	if item.HardExpiration.IsZero() {
+		return nil, false
+	}
+
+	now := time.Now()
+
+	if now.Before(item.HardExpiration) {
+		if !item.SoftExpiration.IsZero() && !now.Before(item.SoftExpiration) {
+			me.refreshAsync(key, opts)
+		}
+
+		me.metrics.IncHits()
+		return item, true
+	}
+
+	if now.Before(item.HardExpiration.Add(me.ttl)) {
+		me.metrics.IncMisses()
+		fireExpire(me.hooks, key, item)
+		me.refreshAsync(key, opts)
+		return item, true
+	}
+
+	return nil, false
 }
 
-func (me *coalescedCache) Set(key string, item *Item) {
-	if me.ttl > 0 {
-		item.Expiration = time.Now().Add(me.ttl)
+func (me *coalescedCache) setItem(key string, item *Item, opts callOptions) {
+	ttl := me.ttl
+	if opts.ttlSet {
+		ttl = opts.ttl
+	}
+
+	if ttl > 0 {
+		now := time.Now()
+		hard := now.Add(ttl)
+		item.Expiration = hard
+		item.HardExpiration = hard
+
+		if me.earlyRefreshThreshold > 0 {
+			item.SoftExpiration = now.Add(time.Duration(float64(ttl) * me.earlyRefreshThreshold))
+		}
 	}
 
 	me.entries.Store(key, item)
 }
 
+func (me *coalescedCache) Set(key string, item *Item, opts ...Option) {
+	me.setItem(key, item, newCallOptions(opts...))
+	publishInvalidate(me.eventBus, me.origin, key)
+}
+
+func (me *coalescedCache) Delete(key string) error {
+	me.entries.Delete(key)
+
+	if me.eventBus == nil {
+		return nil
+	}
+
+	return me.eventBus.Publish(me.origin, key)
+}
+
 // NewCoalescedCache returns an instance of coalescedCache
-func NewCoalescedCache(store Adapter, ttl time.Duration) *coalescedCache {
+func NewCoalescedCache(store Adapter, ttl time.Duration, opts ...CacheOption) *coalescedCache {
+	cfg := newCacheConfig(opts...)
 	var entries sync.Map
-	return &coalescedCache{store: store, entries: entries, ttl: ttl}
+	me := &coalescedCache{
+		store:                 store,
+		entries:               entries,
+		ttl:                   ttl,
+		earlyRefreshThreshold: cfg.earlyRefreshThreshold,
+		metrics:               cfg.metrics,
+		hooks:                 cfg.hooks,
+		eventBus:              cfg.eventBus,
+	}
+	me.origin = fmt.Sprintf("%p", me)
+
+	if me.eventBus != nil {
+		me.eventBus.Subscribe(func(origin, key string) {
+			if origin == me.origin {
+				return
+			}
+			me.entries.Delete(key)
+		})
+	}
+
+	return me
 }
@@ -0,0 +1,59 @@
+package cache
+
+import "time"
+
+// Option configures an individual Get or Set call, overriding the cache's
+// constructor-time defaults for that call only.
+type Option func(*callOptions)
+
+type callOptions struct {
+	ttl     time.Duration
+	ttlSet  bool
+	loader  func(key string) (*Item, error)
+	cost    int64
+	staleOK time.Duration
+}
+
+func newCallOptions(opts ...Option) callOptions {
+	var o callOptions
+	o.cost = 1
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// WithTTL overrides the cache's constructor TTL for this call.
+func WithTTL(d time.Duration) Option {
+	return func(o *callOptions) {
+		o.ttl = d
+		o.ttlSet = true
+	}
+}
+
+// WithLoader overrides the cache's Adapter.Query for this call, useful when
+// the same cache services multiple data sources.
+func WithLoader(loader func(key string) (*Item, error)) Option {
+	return func(o *callOptions) {
+		o.loader = loader
+	}
+}
+
+// WithCost attaches a cost to the item being Set. Caches that evict by
+// cumulative cost, such as boundedCache, weigh the entry accordingly;
+// caches that don't evict by cost ignore it. Defaults to 1.
+func WithCost(cost int64) Option {
+	return func(o *callOptions) {
+		o.cost = cost
+	}
+}
+
+// WithStaleOK lets Get return an expired item if refetching it fails,
+// provided the item expired no more than d ago.
+func WithStaleOK(d time.Duration) Option {
+	return func(o *callOptions) {
+		o.staleOK = d
+	}
+}
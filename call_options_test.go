@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dkrichards86/gocache/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions_WithTTL_OverridesConstructorTTL(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockAdapter := mocks.NewMockAdapter(mockCtrl)
+	mockAdapter.EXPECT().Query(testKey).AnyTimes().Return(testElem, nil)
+
+	testCache := NewLockedCache(mockAdapter, time.Hour)
+	item, err := testCache.Get(testKey, WithTTL(time.Microsecond))
+	require.NoError(t, err)
+	assert.True(t, item.Expiration.Before(time.Now().Add(time.Second)))
+}
+
+func TestOptions_WithLoader_OverridesAdapter(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockAdapter := mocks.NewMockAdapter(mockCtrl)
+	mockAdapter.EXPECT().Query(gomock.Any()).Times(0)
+
+	testCache := NewLockedCache(mockAdapter, time.Microsecond)
+	loader := func(key string) (*Item, error) {
+		return &Item{Value: "from loader"}, nil
+	}
+
+	item, err := testCache.Get(testKey, WithLoader(loader))
+	require.NoError(t, err)
+	assert.Equal(t, "from loader", item.Value)
+}
+
+func TestOptions_WithStaleOK_ReturnsExpiredItemOnRefetchFailure(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockAdapter := mocks.NewMockAdapter(mockCtrl)
+	mockAdapter.EXPECT().Query(testKey).Return(testElem, nil)
+
+	testCache := NewLockedCache(mockAdapter, time.Microsecond)
+	_, err := testCache.Get(testKey)
+	require.NoError(t, err)
+
+	time.Sleep(time.Millisecond)
+
+	failingLoader := func(key string) (*Item, error) {
+		return nil, errors.New("origin down")
+	}
+
+	item, err := testCache.Get(testKey, WithLoader(failingLoader), WithStaleOK(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, testElem.Value, item.Value)
+}
+
+func TestOptions_WithStaleOK_OutsideGraceWindowPropagatesError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockAdapter := mocks.NewMockAdapter(mockCtrl)
+	mockAdapter.EXPECT().Query(testKey).Return(testElem, nil)
+
+	testCache := NewLockedCache(mockAdapter, time.Microsecond)
+	_, err := testCache.Get(testKey)
+	require.NoError(t, err)
+
+	time.Sleep(time.Millisecond)
+
+	failingLoader := func(key string) (*Item, error) {
+		return nil, errors.New("origin down")
+	}
+
+	_, err = testCache.Get(testKey, WithLoader(failingLoader), WithStaleOK(time.Nanosecond))
+	assert.Error(t, err)
+}
+
+func TestOptions_WithCost_EvictsByCumulativeCost(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockAdapter := mocks.NewMockAdapter(mockCtrl)
+	mockAdapter.EXPECT().Query(gomock.Any()).AnyTimes().Return(testElem, nil)
+
+	testCache := NewBoundedCache(mockAdapter, time.Duration(0), 3)
+	defer testCache.Stop()
+
+	testCache.Set("a", &Item{Value: "a"}, WithCost(2))
+	testCache.Set("b", &Item{Value: "b"}, WithCost(2))
+
+	assert.Equal(t, 1, testCache.Len())
+	_, ok := testCache.entries["a"]
+	assert.False(t, ok, "a should have been evicted to stay under the cost budget")
+}
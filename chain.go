@@ -0,0 +1,70 @@
+package cache
+
+// chainCache queries a sequence of Cache layers, shallowest first, on Get,
+// and promotes a hit to every shallower layer so it's warm there next time.
+// Set writes through to every layer. An Adapter error only propagates to
+// the caller once every layer has missed.
+type chainCache struct {
+	layers []Cache
+}
+
+// NewChainCache returns a tiered Cache over layers, ordered shallowest
+// (checked, and promoted to, first) to deepest.
+func NewChainCache(layers ...Cache) *chainCache {
+	return &chainCache{layers: layers}
+}
+
+func (me *chainCache) Get(key string, opts ...Option) (*Item, error) {
+	var lastErr error
+
+	for i, layer := range me.layers {
+		item, err := layer.Get(key, opts...)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		// item is the live pointer stored in layer's own entries map, not
+		// a copy, so it can't be annotated in place: a concurrent Get
+		// hitting the same layer would race on this write, and it would
+		// leak chain bookkeeping into that layer's own stored entry.
+		cp := *item
+		me.promote(key, &cp, i, opts...)
+		cp.Source = i
+		return &cp, nil
+	}
+
+	return nil, lastErr
+}
+
+// promote writes item to every layer shallower than fromIdx, the layer that
+// actually served it. Each layer gets its own copy of item: a layer's Set
+// mutates the Item it's given (e.g. its Expiration), and layers can have
+// different TTLs.
+func (me *chainCache) promote(key string, item *Item, fromIdx int, opts ...Option) {
+	for i := 0; i < fromIdx; i++ {
+		copied := *item
+		me.layers[i].Set(key, &copied, opts...)
+	}
+}
+
+// Set writes item to every layer, each its own copy, for the same reason
+// promote copies per layer.
+func (me *chainCache) Set(key string, item *Item, opts ...Option) {
+	for _, layer := range me.layers {
+		copied := *item
+		layer.Set(key, &copied, opts...)
+	}
+}
+
+func (me *chainCache) Delete(key string) error {
+	var firstErr error
+
+	for _, layer := range me.layers {
+		if err := layer.Delete(key); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
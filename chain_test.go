@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type erroringAdapter struct {
+	err error
+}
+
+func (me *erroringAdapter) Query(key string) (interface{}, error) {
+	return nil, me.err
+}
+
+func TestChainCache_PromotesHitsToShallowerLayers(t *testing.T) {
+	origin := &fakeStringAdapter{value: testElem}
+	l0 := NewLockedCache(&erroringAdapter{err: errors.New("l0 miss")}, time.Hour)
+	l1 := NewLockedCache(origin, time.Hour)
+
+	testCache := NewChainCache(l0, l1)
+
+	item, err := testCache.Get(testKey)
+	require.NoError(t, err)
+	assert.Equal(t, 1, item.Source)
+
+	// l0's own Adapter still errors, but the hit from l1 should have been
+	// promoted directly into l0's entries, so a second Get hits there.
+	item, err = l0.Get(testKey)
+	require.NoError(t, err)
+	assert.Equal(t, testElem.Value, item.Value)
+}
+
+func TestChainCache_PromoteDoesNotCorruptDeeperLayerTTL(t *testing.T) {
+	origin := &fakeStringAdapter{value: testElem}
+	l0 := NewLockedCache(&erroringAdapter{err: errors.New("l0 miss")}, time.Millisecond)
+	l1 := NewLockedCache(origin, time.Hour)
+
+	testCache := NewChainCache(l0, l1)
+
+	_, err := testCache.Get(testKey)
+	require.NoError(t, err)
+
+	stored, ok := l1.entries.Load(testKey)
+	require.True(t, ok)
+	assert.True(t, time.Now().Add(time.Minute).Before(stored.(*Item).Expiration),
+		"l1's own entry should keep its hour-long TTL, not l0's millisecond one")
+}
+
+func TestChainCache_GetDoesNotMutateServingLayersStoredItem(t *testing.T) {
+	origin := &fakeStringAdapter{value: testElem}
+	l0 := NewLockedCache(&erroringAdapter{err: errors.New("l0 miss")}, time.Hour)
+	l1 := NewLockedCache(origin, time.Hour)
+
+	testCache := NewChainCache(l0, l1)
+
+	// Warm l1 directly so every chainCache.Get below hits the same stored
+	// *Item in l1's own entries rather than a freshly fetched one.
+	_, err := l1.Get(testKey)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = testCache.Get(testKey)
+		}()
+	}
+	wg.Wait()
+
+	stored, ok := l1.entries.Load(testKey)
+	require.True(t, ok)
+	assert.Equal(t, 0, stored.(*Item).Source,
+		"chainCache.Get must not annotate the Item stored in the layer that served it")
+}
+
+func TestChainCache_ErrorOnlyPropagatesWhenEveryLayerMisses(t *testing.T) {
+	boom := errors.New("boom")
+	l0 := NewLockedCache(&erroringAdapter{err: boom}, time.Hour)
+	l1 := NewLockedCache(&erroringAdapter{err: boom}, time.Hour)
+
+	testCache := NewChainCache(l0, l1)
+
+	_, err := testCache.Get(testKey)
+	assert.ErrorIs(t, err, boom)
+}
+
+type fakeStringAdapter struct {
+	value *Item
+}
+
+func (me *fakeStringAdapter) Query(key string) (interface{}, error) {
+	return me.value, nil
+}
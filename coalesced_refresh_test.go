@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingAdapter struct {
+	mu    sync.Mutex
+	calls int32
+	value *Item
+}
+
+func (me *countingAdapter) Query(key string) (interface{}, error) {
+	atomic.AddInt32(&me.calls, 1)
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	return me.value, nil
+}
+
+func (me *countingAdapter) Calls() int32 {
+	return atomic.LoadInt32(&me.calls)
+}
+
+func TestCoalescedCache_EarlyRefresh_ServesStaleWhileRefreshing(t *testing.T) {
+	adapter := &countingAdapter{value: &Item{Value: "v1"}}
+	testCache := NewCoalescedCache(adapter, 20*time.Millisecond, WithEarlyRefresh(0.5))
+
+	item, err := testCache.Get(testKey)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", item.Value)
+	assert.EqualValues(t, 1, adapter.Calls())
+
+	time.Sleep(12 * time.Millisecond) // past the 50% soft-expiration threshold
+
+	item, err = testCache.Get(testKey)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", item.Value, "should serve the still-fresh stale value immediately")
+
+	assert.Eventually(t, func() bool {
+		return adapter.Calls() == 2
+	}, time.Second, time.Millisecond, "background refresh should have run exactly once")
+}
+
+func TestCoalescedCache_EarlyRefresh_NoRaceBetweenReadersAndBackgroundRefresh(t *testing.T) {
+	adapter := &countingAdapter{value: &Item{Value: "v1"}}
+	testCache := NewCoalescedCache(adapter, 15*time.Millisecond, WithEarlyRefresh(0.1))
+
+	_, err := testCache.Get(testKey)
+	require.NoError(t, err)
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					_, _ = testCache.Get(testKey)
+				}
+			}
+		}()
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	close(stop)
+	wg.Wait()
+}
+
+func TestCoalescedCache_EarlyRefresh_ServesStaleAfterHardExpiration(t *testing.T) {
+	adapter := &countingAdapter{value: &Item{Value: "v1"}}
+	testCache := NewCoalescedCache(adapter, 10*time.Millisecond, WithEarlyRefresh(0.99))
+
+	_, err := testCache.Get(testKey)
+	require.NoError(t, err)
+
+	time.Sleep(15 * time.Millisecond) // past HardExpiration, within the grace window
+
+	start := time.Now()
+	item, err := testCache.Get(testKey)
+	elapsed := time.Since(start)
+	require.NoError(t, err)
+
+	assert.Equal(t, "v1", item.Value)
+	assert.Less(t, elapsed, 5*time.Millisecond, "should not block on a synchronous refetch")
+}
@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+)
+
+// Codec marshals and unmarshals an Item for storage in an out-of-process
+// backend such as Redis or Memcache.
+type Codec interface {
+	Marshal(item *Item) ([]byte, error)
+	Unmarshal(data []byte) (*Item, error)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(item *Item) ([]byte, error) {
+	return json.Marshal(item)
+}
+
+func (jsonCodec) Unmarshal(data []byte) (*Item, error) {
+	var item Item
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// NewJSONCodec returns a Codec that (de)serializes Items as JSON.
+func NewJSONCodec() Codec {
+	return jsonCodec{}
+}
+
+type gobCodec struct{}
+
+func (gobCodec) Marshal(item *Item) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(item); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte) (*Item, error) {
+	var item Item
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&item); err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// NewGobCodec returns a Codec that (de)serializes Items with encoding/gob.
+// Item.Value is stored as an interface{}, so callers must gob.Register its
+// concrete type before encoding or decoding.
+func NewGobCodec() Codec {
+	return gobCodec{}
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"encoding/gob"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	gob.Register("")
+}
+
+func TestJSONCodec_RoundTrip(t *testing.T) {
+	codec := NewJSONCodec()
+
+	data, err := codec.Marshal(&Item{Value: "hey oh"})
+	require.NoError(t, err)
+
+	item, err := codec.Unmarshal(data)
+	require.NoError(t, err)
+	assert.Equal(t, "hey oh", item.Value)
+}
+
+func TestGobCodec_RoundTrip(t *testing.T) {
+	codec := NewGobCodec()
+
+	data, err := codec.Marshal(&Item{Value: "hey oh"})
+	require.NoError(t, err)
+
+	item, err := codec.Unmarshal(data)
+	require.NoError(t, err)
+	assert.Equal(t, "hey oh", item.Value)
+}
+
+type fakeRedisClient struct {
+	data map[string][]byte
+}
+
+func (me *fakeRedisClient) Get(key string) ([]byte, error) {
+	return me.data[key], nil
+}
+
+func TestRedisAdapter_Query(t *testing.T) {
+	codec := NewJSONCodec()
+	data, err := codec.Marshal(&Item{Value: "hey oh"})
+	require.NoError(t, err)
+
+	client := &fakeRedisClient{data: map[string][]byte{testKey: data}}
+	adapter := NewRedisAdapter(client, codec)
+
+	value, err := adapter.Query(testKey)
+	require.NoError(t, err)
+	assert.Equal(t, "hey oh", value.(*Item).Value)
+}
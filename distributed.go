@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// PeerClient fetches a key from one specific remote peer.
+type PeerClient interface {
+	Fetch(ctx context.Context, key string) (*Item, error)
+}
+
+// PeerPicker resolves which peer owns a given key. isSelf is true when the
+// local process owns the key, in which case peer is nil.
+type PeerPicker interface {
+	PickPeer(key string) (peer PeerClient, isSelf bool, err error)
+}
+
+// distributedCache shares one logical cache across a cluster of processes:
+// PeerPicker's consistent hashing sends each key to exactly one owning
+// peer, so the origin behind Adapter only ever sees roughly 1/N of the
+// load. Set only ever writes to the local shard; there's no peer-to-peer
+// write path, mirroring the read-through model this is based on.
+type distributedCache struct {
+	self  string
+	peers PeerPicker
+	store Adapter
+
+	ttl time.Duration
+
+	entries      sync.Map
+	singleflight singleflight.Group
+}
+
+// NewDistributedCache returns a distributedCache that answers for self and
+// reaches its peers through peers. If peers is an *HTTPPool, it's bound to
+// this cache so its ServeHTTP can answer other peers' requests for keys
+// this process owns.
+func NewDistributedCache(self string, peers PeerPicker, store Adapter, ttl time.Duration) *distributedCache {
+	me := &distributedCache{self: self, peers: peers, store: store, ttl: ttl}
+
+	if pool, ok := peers.(*HTTPPool); ok {
+		pool.bind(me)
+	}
+
+	return me
+}
+
+func (me *distributedCache) Get(key string, opts ...Option) (*Item, error) {
+	peer, isSelf, err := me.peers.PickPeer(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isSelf {
+		return peer.Fetch(context.Background(), key)
+	}
+
+	return me.getLocal(key, opts...)
+}
+
+// getLocal answers for key out of this process's own entries/Adapter,
+// regardless of who currently owns it on the ring. HTTPPool.ServeHTTP calls
+// this directly to answer a remote peer's request.
+func (me *distributedCache) getLocal(key string, opts ...Option) (*Item, error) {
+	o := newCallOptions(opts...)
+
+	value, err, _ := me.singleflight.Do(key, func() (interface{}, error) {
+		if v, ok := me.entries.Load(key); ok {
+			if item := v.(*Item); !item.Expired(time.Now()) {
+				return item, nil
+			}
+		}
+
+		value, err := query(me.store, key, o)
+		if err != nil {
+			return nil, err
+		}
+
+		item := value.(*Item)
+		me.setLocal(key, item, o)
+		return item, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return value.(*Item), nil
+}
+
+func (me *distributedCache) setLocal(key string, item *Item, opts callOptions) {
+	ttl := me.ttl
+	if opts.ttlSet {
+		ttl = opts.ttl
+	}
+
+	if ttl > 0 {
+		item.Expiration = time.Now().Add(ttl)
+	}
+
+	me.entries.Store(key, item)
+}
+
+func (me *distributedCache) Set(key string, item *Item, opts ...Option) {
+	me.setLocal(key, item, newCallOptions(opts...))
+}
+
+// Delete removes key from this process's own entries only; like Set, there
+// is no peer-to-peer write path to propagate it across the cluster.
+func (me *distributedCache) Delete(key string) error {
+	me.entries.Delete(key)
+	return nil
+}
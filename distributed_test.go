@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingOriginAdapter simulates the single origin behind a cluster of
+// distributed cache peers, tracking how many times each key was queried so
+// tests can assert only the owning peer ever reaches it.
+type countingOriginAdapter struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func newCountingOriginAdapter() *countingOriginAdapter {
+	return &countingOriginAdapter{calls: make(map[string]int)}
+}
+
+func (me *countingOriginAdapter) Query(key string) (interface{}, error) {
+	me.mu.Lock()
+	me.calls[key]++
+	me.mu.Unlock()
+
+	return &Item{Value: "origin:" + key}, nil
+}
+
+func (me *countingOriginAdapter) callsFor(key string) int {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	return me.calls[key]
+}
+
+func TestDistributedCache_ThreePeers(t *testing.T) {
+	const peerCount = 3
+
+	pools := make([]*HTTPPool, peerCount)
+	servers := make([]*httptest.Server, peerCount)
+
+	for i := 0; i < peerCount; i++ {
+		pool := &HTTPPool{basePath: defaultBasePath}
+		servers[i] = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			pool.ServeHTTP(w, r)
+		}))
+		pools[i] = pool
+	}
+	defer func() {
+		for _, s := range servers {
+			s.Close()
+		}
+	}()
+
+	addrs := make([]string, peerCount)
+	for i, s := range servers {
+		addrs[i] = s.URL
+	}
+
+	origin := newCountingOriginAdapter()
+	caches := make([]*distributedCache, peerCount)
+	for i := range pools {
+		pools[i].self = addrs[i]
+		pools[i].SetPeers(addrs...)
+		caches[i] = NewDistributedCache(addrs[i], pools[i], origin, time.Minute)
+	}
+
+	for k := 0; k < 10; k++ {
+		key := fmt.Sprintf("key-%d", k)
+		expected := "origin:" + key
+
+		for _, c := range caches {
+			item, err := c.Get(key)
+			require.NoError(t, err)
+			assert.Equal(t, expected, item.Value)
+		}
+
+		assert.Equal(t, 1, origin.callsFor(key), "only the owning peer should reach the origin")
+	}
+}
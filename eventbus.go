@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"strings"
+	"sync"
+)
+
+// EventBus lets a fleet of cache processes stay coherent without every read
+// hitting a shared backend: Set publishes the changed key, and every
+// process subscribed to the bus deletes that key locally so its next Get
+// re-fetches from Adapter. origin identifies the publishing cache so a
+// subscriber can tell its own publishes apart from a peer's; a cache that
+// stored key locally before publishing would otherwise immediately delete
+// it again when its own publish is delivered back to it.
+type EventBus interface {
+	Publish(origin, key string) error
+	Subscribe(handler func(origin, key string)) error
+}
+
+// publishInvalidate publishes key on bus if one was configured, swallowing
+// any error since Set has no error return to surface it through.
+func publishInvalidate(bus EventBus, origin, key string) {
+	if bus != nil {
+		_ = bus.Publish(origin, key)
+	}
+}
+
+type localEventBus struct {
+	mu       sync.Mutex
+	handlers []func(origin, key string)
+}
+
+// NewLocalEventBus returns an in-process EventBus. It's useful for tests
+// and for wiring multiple caches within the same process to the same
+// invalidation stream; it does not reach across processes.
+func NewLocalEventBus() EventBus {
+	return &localEventBus{}
+}
+
+func (me *localEventBus) Publish(origin, key string) error {
+	me.mu.Lock()
+	handlers := make([]func(origin, key string), len(me.handlers))
+	copy(handlers, me.handlers)
+	me.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(origin, key)
+	}
+
+	return nil
+}
+
+func (me *localEventBus) Subscribe(handler func(origin, key string)) error {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+
+	me.handlers = append(me.handlers, handler)
+	return nil
+}
+
+// RedisPubSubClient is the subset of a Redis client NewRedisEventBus needs.
+type RedisPubSubClient interface {
+	Publish(channel, message string) error
+	Subscribe(channel string, handler func(message string)) error
+}
+
+type redisEventBus struct {
+	client  RedisPubSubClient
+	channel string
+}
+
+// NewRedisEventBus returns an EventBus backed by Redis pub/sub on channel,
+// letting a fleet of processes share a consistent cache view without every
+// read hitting Redis.
+func NewRedisEventBus(client RedisPubSubClient, channel string) EventBus {
+	return &redisEventBus{client: client, channel: channel}
+}
+
+// originSeparator joins origin and key into the single message string
+// RedisPubSubClient carries; cache keys aren't expected to contain a NUL
+// byte.
+const originSeparator = "\x00"
+
+func (me *redisEventBus) Publish(origin, key string) error {
+	return me.client.Publish(me.channel, origin+originSeparator+key)
+}
+
+func (me *redisEventBus) Subscribe(handler func(origin, key string)) error {
+	return me.client.Subscribe(me.channel, func(message string) {
+		origin, key, ok := strings.Cut(message, originSeparator)
+		if !ok {
+			return
+		}
+
+		handler(origin, key)
+	})
+}
@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dkrichards86/gocache/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedisPubSubClient is an in-memory RedisPubSubClient: Publish calls every
+// handler subscribed to channel synchronously, same as a real Redis
+// pub/sub delivering to a local subscriber.
+type fakeRedisPubSubClient struct {
+	mu       sync.Mutex
+	handlers map[string][]func(message string)
+}
+
+func newFakeRedisPubSubClient() *fakeRedisPubSubClient {
+	return &fakeRedisPubSubClient{handlers: make(map[string][]func(message string))}
+}
+
+func (me *fakeRedisPubSubClient) Publish(channel, message string) error {
+	me.mu.Lock()
+	handlers := make([]func(message string), len(me.handlers[channel]))
+	copy(handlers, me.handlers[channel])
+	me.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(message)
+	}
+
+	return nil
+}
+
+func (me *fakeRedisPubSubClient) Subscribe(channel string, handler func(message string)) error {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+
+	me.handlers[channel] = append(me.handlers[channel], handler)
+	return nil
+}
+
+func TestEventBus_SetInvalidatesOtherCacheSharingTheBus(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockAdapter := mocks.NewMockAdapter(mockCtrl)
+	mockAdapter.EXPECT().Query(testKey).Times(2).Return(testElem, nil)
+
+	bus := NewLocalEventBus()
+	cacheA := NewLockedCache(mockAdapter, time.Hour, WithEventBus(bus))
+	cacheB := NewLockedCache(mockAdapter, time.Hour, WithEventBus(bus))
+
+	_, err := cacheA.Get(testKey)
+	require.NoError(t, err)
+	_, err = cacheB.Get(testKey)
+	require.NoError(t, err)
+
+	cacheA.Set(testKey, &Item{Value: "updated"})
+
+	item, ok := cacheB.entries.Load(testKey)
+	assert.False(t, ok, "cacheB should have invalidated its entry when cacheA published")
+	_ = item
+}
+
+func TestEventBus_SetDoesNotInvalidateItsOwnEntry(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockAdapter := mocks.NewMockAdapter(mockCtrl)
+
+	bus := NewLocalEventBus()
+	cacheA := NewLockedCache(mockAdapter, time.Hour, WithEventBus(bus))
+
+	cacheA.Set(testKey, &Item{Value: "updated"})
+
+	item, ok := cacheA.entries.Load(testKey)
+	require.True(t, ok, "cacheA should not have invalidated the entry it just set")
+	assert.Equal(t, "updated", item.(*Item).Value)
+}
+
+func TestEventBus_Delete_RemovesLocallyAndPublishes(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockAdapter := mocks.NewMockAdapter(mockCtrl)
+	mockAdapter.EXPECT().Query(testKey).AnyTimes().Return(testElem, nil)
+
+	bus := NewLocalEventBus()
+	cacheA := NewCoalescedCache(mockAdapter, time.Hour, WithEventBus(bus))
+	cacheB := NewCoalescedCache(mockAdapter, time.Hour, WithEventBus(bus))
+
+	_, err := cacheA.Get(testKey)
+	require.NoError(t, err)
+	_, err = cacheB.Get(testKey)
+	require.NoError(t, err)
+
+	err = cacheA.Delete(testKey)
+	require.NoError(t, err)
+
+	_, ok := cacheA.entries.Load(testKey)
+	assert.False(t, ok)
+	_, ok = cacheB.entries.Load(testKey)
+	assert.False(t, ok)
+}
+
+func TestRedisEventBus_PublishRoundTripsOriginAndKey(t *testing.T) {
+	bus := NewRedisEventBus(newFakeRedisPubSubClient(), "invalidations")
+
+	var gotOrigin, gotKey string
+	err := bus.Subscribe(func(origin, key string) {
+		gotOrigin = origin
+		gotKey = key
+	})
+	require.NoError(t, err)
+
+	err = bus.Publish("cacheA", testKey)
+	require.NoError(t, err)
+
+	assert.Equal(t, "cacheA", gotOrigin)
+	assert.Equal(t, testKey, gotKey)
+}
+
+func TestRedisEventBus_InvalidatesOtherCacheOnSameChannel(t *testing.T) {
+	client := newFakeRedisPubSubClient()
+
+	mockCtrl := gomock.NewController(t)
+	mockAdapter := mocks.NewMockAdapter(mockCtrl)
+	mockAdapter.EXPECT().Query(testKey).Times(2).Return(testElem, nil)
+
+	cacheA := NewLockedCache(mockAdapter, time.Hour, WithEventBus(NewRedisEventBus(client, "invalidations")))
+	cacheB := NewLockedCache(mockAdapter, time.Hour, WithEventBus(NewRedisEventBus(client, "invalidations")))
+
+	_, err := cacheA.Get(testKey)
+	require.NoError(t, err)
+	_, err = cacheB.Get(testKey)
+	require.NoError(t, err)
+
+	cacheA.Set(testKey, &Item{Value: "updated"})
+
+	_, ok := cacheA.entries.Load(testKey)
+	assert.True(t, ok, "cacheA should not invalidate the entry it just published")
+	_, ok = cacheB.entries.Load(testKey)
+	assert.False(t, ok, "cacheB should invalidate its entry when cacheA publishes over Redis")
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+)
+
+type hashFunc func(data []byte) uint32
+
+// hashRing is a consistent-hashing ring of peer addresses, used by HTTPPool
+// to pick which peer owns a given key. Each peer gets `replicas` virtual
+// nodes on the ring so load spreads evenly as peers join or leave.
+type hashRing struct {
+	hash     hashFunc
+	replicas int
+	keys     []int
+	peers    map[int]string
+}
+
+func newHashRing(replicas int, fn hashFunc) *hashRing {
+	if fn == nil {
+		fn = crc32.ChecksumIEEE
+	}
+
+	return &hashRing{hash: fn, replicas: replicas, peers: make(map[int]string)}
+}
+
+// add registers peers on the ring.
+func (me *hashRing) add(peers ...string) {
+	for _, peer := range peers {
+		for i := 0; i < me.replicas; i++ {
+			hash := int(me.hash([]byte(strconv.Itoa(i) + peer)))
+			me.keys = append(me.keys, hash)
+			me.peers[hash] = peer
+		}
+	}
+
+	sort.Ints(me.keys)
+}
+
+// get returns the peer owning key, or "" if the ring has no peers.
+func (me *hashRing) get(key string) string {
+	if len(me.keys) == 0 {
+		return ""
+	}
+
+	hash := int(me.hash([]byte(key)))
+	idx := sort.Search(len(me.keys), func(i int) bool { return me.keys[i] >= hash })
+	if idx == len(me.keys) {
+		idx = 0
+	}
+
+	return me.peers[me.keys[idx]]
+}
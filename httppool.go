@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const defaultBasePath = "/_cache/"
+
+// HTTPPool is a PeerPicker and http.Handler: it picks a peer for a key via
+// consistent hashing and, once bound to a distributedCache by
+// NewDistributedCache, serves other peers' requests for keys this process
+// owns at {basePath}{key}.
+type HTTPPool struct {
+	self     string
+	basePath string
+
+	mu    sync.Mutex
+	ring  *hashRing
+	peers map[string]*httpPeerClient
+
+	cache *distributedCache
+}
+
+// NewHTTPPool returns an HTTPPool for self, already aware of peerAddrs.
+// self and every entry in peerAddrs must be reachable base URLs, e.g.
+// "http://10.0.0.1:8080".
+func NewHTTPPool(self string, peerAddrs ...string) *HTTPPool {
+	pool := &HTTPPool{self: self, basePath: defaultBasePath}
+	pool.SetPeers(peerAddrs...)
+	return pool
+}
+
+// SetPeers replaces the pool's peer set, rebuilding the consistent-hashing
+// ring. Only peerAddrs are placed on the ring: self owns keys only when it
+// is included in peerAddrs.
+func (me *HTTPPool) SetPeers(peerAddrs ...string) {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+
+	ring := newHashRing(50, nil)
+	ring.add(peerAddrs...)
+
+	peers := make(map[string]*httpPeerClient, len(peerAddrs))
+	for _, addr := range peerAddrs {
+		peers[addr] = &httpPeerClient{baseURL: addr + me.basePath}
+	}
+
+	me.ring = ring
+	me.peers = peers
+}
+
+// PickPeer implements PeerPicker.
+func (me *HTTPPool) PickPeer(key string) (PeerClient, bool, error) {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+
+	addr := me.ring.get(key)
+	if addr == "" || addr == me.self {
+		return nil, true, nil
+	}
+
+	return me.peers[addr], false, nil
+}
+
+func (me *HTTPPool) bind(cache *distributedCache) {
+	me.cache = cache
+}
+
+// ServeHTTP answers GET {basePath}{key} with the local shard's Item,
+// fetching it from the Adapter on a miss.
+func (me *HTTPPool) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, me.basePath) {
+		http.Error(w, "bad request path", http.StatusBadRequest)
+		return
+	}
+
+	key := r.URL.Path[len(me.basePath):]
+	if key == "" {
+		http.Error(w, "missing key", http.StatusBadRequest)
+		return
+	}
+
+	if me.cache == nil {
+		http.Error(w, "pool is not bound to a cache", http.StatusInternalServerError)
+		return
+	}
+
+	item, err := me.cache.getLocal(key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(item)
+}
+
+// httpPeerClient is the PeerClient that calls a remote HTTPPool's
+// ServeHTTP.
+type httpPeerClient struct {
+	baseURL string
+}
+
+func (me *httpPeerClient) Fetch(ctx context.Context, key string) (*Item, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, me.baseURL+key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("peer %s: %s: %s", me.baseURL, resp.Status, body)
+	}
+
+	var item Item
+	if err := json.NewDecoder(resp.Body).Decode(&item); err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
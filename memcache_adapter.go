@@ -0,0 +1,28 @@
+package cache
+
+// MemcacheClient is the subset of a Memcache client MemcacheAdapter needs.
+type MemcacheClient interface {
+	Get(key string) ([]byte, error)
+}
+
+// MemcacheAdapter satisfies Adapter by decoding codec-encoded Items read
+// from Memcache.
+type MemcacheAdapter struct {
+	client MemcacheClient
+	codec  Codec
+}
+
+// NewMemcacheAdapter returns a MemcacheAdapter that decodes values read
+// from client with codec.
+func NewMemcacheAdapter(client MemcacheClient, codec Codec) *MemcacheAdapter {
+	return &MemcacheAdapter{client: client, codec: codec}
+}
+
+func (me *MemcacheAdapter) Query(key string) (interface{}, error) {
+	data, err := me.client.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return me.codec.Unmarshal(data)
+}
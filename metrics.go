@@ -0,0 +1,55 @@
+package cache
+
+import "time"
+
+// Metrics receives counters and timings for cache activity. Implementations
+// must be safe for concurrent use.
+type Metrics interface {
+	IncHits()
+	IncMisses()
+	IncEvictions()
+	IncLoaderErrors()
+	ObserveLoadDuration(d time.Duration)
+}
+
+// Hooks receives lifecycle events as entries move through a cache.
+// Implementations must be safe for concurrent use.
+type Hooks interface {
+	OnInsert(key string, item *Item)
+	OnEvict(key string, item *Item)
+	OnExpire(key string, item *Item)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) IncHits()                            {}
+func (noopMetrics) IncMisses()                          {}
+func (noopMetrics) IncEvictions()                       {}
+func (noopMetrics) IncLoaderErrors()                    {}
+func (noopMetrics) ObserveLoadDuration(d time.Duration) {}
+
+// NewNoopMetrics returns a Metrics implementation that discards everything.
+// It's the default when a cache is constructed without WithMetrics.
+func NewNoopMetrics() Metrics {
+	return noopMetrics{}
+}
+
+// fireInsert, fireEvict, and fireExpire call the corresponding Hooks method
+// if hooks were configured via WithHooks; otherwise they're no-ops.
+func fireInsert(h Hooks, key string, item *Item) {
+	if h != nil {
+		h.OnInsert(key, item)
+	}
+}
+
+func fireEvict(h Hooks, key string, item *Item) {
+	if h != nil {
+		h.OnEvict(key, item)
+	}
+}
+
+func fireExpire(h Hooks, key string, item *Item) {
+	if h != nil {
+		h.OnExpire(key, item)
+	}
+}
@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dkrichards86/gocache/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMetrics struct {
+	hits, misses, evictions, loaderErrors int
+}
+
+func (me *fakeMetrics) IncHits()                            { me.hits++ }
+func (me *fakeMetrics) IncMisses()                          { me.misses++ }
+func (me *fakeMetrics) IncEvictions()                       { me.evictions++ }
+func (me *fakeMetrics) IncLoaderErrors()                    { me.loaderErrors++ }
+func (me *fakeMetrics) ObserveLoadDuration(d time.Duration) {}
+
+type fakeHooks struct {
+	inserted, evicted, expired []string
+}
+
+func (me *fakeHooks) OnInsert(key string, item *Item) { me.inserted = append(me.inserted, key) }
+func (me *fakeHooks) OnEvict(key string, item *Item)  { me.evicted = append(me.evicted, key) }
+func (me *fakeHooks) OnExpire(key string, item *Item) { me.expired = append(me.expired, key) }
+
+func TestMetrics_HitsAndMisses(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockAdapter := mocks.NewMockAdapter(mockCtrl)
+	mockAdapter.EXPECT().Query(testKey).AnyTimes().Return(testElem, nil)
+
+	metrics := &fakeMetrics{}
+	testCache := NewLockedCache(mockAdapter, time.Hour, WithMetrics(metrics))
+
+	_, err := testCache.Get(testKey)
+	require.NoError(t, err)
+	_, err = testCache.Get(testKey)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, metrics.misses)
+	assert.Equal(t, 1, metrics.hits)
+}
+
+func TestMetrics_Hooks_InsertAndEvict(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockAdapter := mocks.NewMockAdapter(mockCtrl)
+	mockAdapter.EXPECT().Query(gomock.Any()).AnyTimes().Return(testElem, nil)
+
+	metrics := &fakeMetrics{}
+	hooks := &fakeHooks{}
+	testCache := NewBoundedCache(mockAdapter, time.Duration(0), 1, WithMetrics(metrics), WithHooks(hooks))
+	defer testCache.Stop()
+
+	testCache.Set("a", &Item{Value: "a"})
+	testCache.Set("b", &Item{Value: "b"})
+
+	assert.Equal(t, []string{"a"}, hooks.evicted)
+	assert.Equal(t, 1, metrics.evictions)
+}
@@ -0,0 +1,75 @@
+package cache
+
+import "time"
+
+// CacheOption configures a cache at construction time.
+type CacheOption func(*cacheConfig)
+
+// cacheConfig holds the resolved set of construction-time options. Not every
+// field applies to every cache implementation; each constructor reads only
+// the fields it understands.
+type cacheConfig struct {
+	janitorTick time.Duration
+	metrics     Metrics
+	hooks       Hooks
+
+	earlyRefreshThreshold float64
+
+	eventBus EventBus
+}
+
+func newCacheConfig(opts ...CacheOption) cacheConfig {
+	cfg := cacheConfig{
+		janitorTick: time.Second,
+		metrics:     NewNoopMetrics(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// WithJanitorTick overrides how often a NewBoundedCache's janitor wakes to
+// re-check the expiration queue when it is empty. The default is one second.
+func WithJanitorTick(d time.Duration) CacheOption {
+	return func(c *cacheConfig) {
+		c.janitorTick = d
+	}
+}
+
+// WithMetrics attaches a Metrics sink to the cache. Without this option,
+// caches record to NewNoopMetrics().
+func WithMetrics(m Metrics) CacheOption {
+	return func(c *cacheConfig) {
+		c.metrics = m
+	}
+}
+
+// WithHooks attaches lifecycle event callbacks to the cache. Without this
+// option, no hooks are called.
+func WithHooks(h Hooks) CacheOption {
+	return func(c *cacheConfig) {
+		c.hooks = h
+	}
+}
+
+// WithEarlyRefresh turns on stale-while-revalidate behavior on
+// coalescedCache. threshold is the fraction of the TTL (0, 1) after which a
+// still-fresh item is returned immediately while a single background
+// singleflight refresh is kicked off. Expired items are likewise served
+// stale, for up to one more TTL, while a background refresh runs.
+func WithEarlyRefresh(threshold float64) CacheOption {
+	return func(c *cacheConfig) {
+		c.earlyRefreshThreshold = threshold
+	}
+}
+
+// WithEventBus attaches an EventBus to a lockedCache or coalescedCache: the
+// cache publishes to it on every Set and Delete, and subscribes to it so
+// any key invalidated elsewhere in the fleet is deleted locally too.
+func WithEventBus(bus EventBus) CacheOption {
+	return func(c *cacheConfig) {
+		c.eventBus = bus
+	}
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type prometheusMetrics struct {
+	hits         prometheus.Counter
+	misses       prometheus.Counter
+	evictions    prometheus.Counter
+	loaderErrors prometheus.Counter
+	loadDuration prometheus.Histogram
+}
+
+func (me *prometheusMetrics) IncHits()         { me.hits.Inc() }
+func (me *prometheusMetrics) IncMisses()       { me.misses.Inc() }
+func (me *prometheusMetrics) IncEvictions()    { me.evictions.Inc() }
+func (me *prometheusMetrics) IncLoaderErrors() { me.loaderErrors.Inc() }
+
+func (me *prometheusMetrics) ObserveLoadDuration(d time.Duration) {
+	me.loadDuration.Observe(d.Seconds())
+}
+
+// NewPrometheusMetrics returns a Metrics implementation that registers its
+// counters and histogram under namespace with registerer. A nil registerer
+// falls back to prometheus.DefaultRegisterer. Callers that need more than
+// one cache sharing a namespace in the same process must pass a distinct
+// registerer per cache, since registering the same metric names twice on
+// one registerer panics.
+func NewPrometheusMetrics(namespace string, registerer prometheus.Registerer) Metrics {
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+
+	me := &prometheusMetrics{
+		hits: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_hits_total",
+			Help:      "Number of cache hits.",
+		}),
+		misses: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_misses_total",
+			Help:      "Number of cache misses.",
+		}),
+		evictions: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_evictions_total",
+			Help:      "Number of cache evictions, including expirations.",
+		}),
+		loaderErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_loader_errors_total",
+			Help:      "Number of Adapter.Query errors.",
+		}),
+		loadDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "cache_load_duration_seconds",
+			Help:      "Time spent in Adapter.Query on a cache miss.",
+		}),
+	}
+
+	registerer.MustRegister(me.hits, me.misses, me.evictions, me.loaderErrors, me.loadDuration)
+
+	return me
+}
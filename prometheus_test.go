@@ -0,0 +1,31 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPrometheusMetrics_DistinctRegisterersDoNotPanic(t *testing.T) {
+	assert.NotPanics(t, func() {
+		NewPrometheusMetrics("gocache", prometheus.NewRegistry())
+		NewPrometheusMetrics("gocache", prometheus.NewRegistry())
+	})
+}
+
+func TestNewPrometheusMetrics_SameRegistererTwicePanics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	NewPrometheusMetrics("gocache", registry)
+
+	assert.Panics(t, func() {
+		NewPrometheusMetrics("gocache", registry)
+	})
+}
+
+func TestNewPrometheusMetrics_NilRegistererUsesDefault(t *testing.T) {
+	metrics := NewPrometheusMetrics("gocache_default_registerer_test", nil)
+	require.NotNil(t, metrics)
+	metrics.IncHits()
+}
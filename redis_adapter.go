@@ -0,0 +1,30 @@
+package cache
+
+// RedisClient is the subset of a Redis client RedisAdapter needs. It's
+// satisfied by most Redis client libraries' Get method directly, or a thin
+// wrapper around one.
+type RedisClient interface {
+	Get(key string) ([]byte, error)
+}
+
+// RedisAdapter satisfies Adapter by decoding codec-encoded Items read from
+// Redis.
+type RedisAdapter struct {
+	client RedisClient
+	codec  Codec
+}
+
+// NewRedisAdapter returns a RedisAdapter that decodes values read from
+// client with codec.
+func NewRedisAdapter(client RedisClient, codec Codec) *RedisAdapter {
+	return &RedisAdapter{client: client, codec: codec}
+}
+
+func (me *RedisAdapter) Query(key string) (interface{}, error) {
+	data, err := me.client.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return me.codec.Unmarshal(data)
+}
@@ -0,0 +1,8 @@
+package v2
+
+//go:generate bin/mockgen -package=mocks -destination=./mocks/mockadapter.go . Adapter
+
+// Adapter is the generic data source a Cache[K, V] falls back to on a miss.
+type Adapter[K comparable, V any] interface {
+	Query(key K) (V, error)
+}
@@ -0,0 +1,196 @@
+package v2
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Item is a value in the cache
+type Item[V any] struct {
+	Value      V
+	Expiration time.Time
+}
+
+// Expired returns true if the item has expired.
+func (me *Item[V]) Expired(now time.Time) bool {
+	if me.Expiration.IsZero() {
+		return false
+	}
+
+	return me.Expiration.Before(now)
+}
+
+// Cache is a generic interface for our cache
+type Cache[K comparable, V any] interface {
+	Get(key K) (Item[V], error)
+	Set(key K, item Item[V])
+}
+
+type simpleCache[K comparable, V any] struct {
+	store Adapter[K, V]
+
+	ttl time.Duration
+
+	entries map[K]Item[V]
+}
+
+func (me *simpleCache[K, V]) fetchAndStore(key K) (Item[V], error) {
+	value, err := me.store.Query(key)
+	if err != nil {
+		return Item[V]{}, err
+	}
+
+	item := Item[V]{Value: value}
+	me.Set(key, item)
+	return item, nil
+}
+
+func (me *simpleCache[K, V]) Get(key K) (Item[V], error) {
+	item, ok := me.entries[key]
+	if !ok {
+		return me.fetchAndStore(key)
+	}
+
+	if item.Expired(time.Now()) {
+		return me.fetchAndStore(key)
+	}
+
+	return item, nil
+}
+
+func (me *simpleCache[K, V]) Set(key K, item Item[V]) {
+	if me.ttl > 0 {
+		item.Expiration = time.Now().Add(me.ttl)
+	}
+
+	me.entries[key] = item
+}
+
+// NewSimpleCache returns an instance of simpleCache
+func NewSimpleCache[K comparable, V any](store Adapter[K, V], ttl time.Duration) *simpleCache[K, V] {
+	entries := make(map[K]Item[V])
+	return &simpleCache[K, V]{store: store, entries: entries, ttl: ttl}
+}
+
+// lockedCache
+type lockedCache[K comparable, V any] struct {
+	store Adapter[K, V]
+
+	ttl time.Duration
+
+	entries sync.Map
+	locks   sync.Map
+}
+
+func (me *lockedCache[K, V]) getLock(key K) *sync.Mutex {
+	rawLock, _ := me.locks.LoadOrStore(key, &sync.Mutex{})
+	return rawLock.(*sync.Mutex)
+}
+
+func (me *lockedCache[K, V]) fetchAndStore(key K) (Item[V], error) {
+	value, err := me.store.Query(key)
+	if err != nil {
+		return Item[V]{}, err
+	}
+
+	item := Item[V]{Value: value}
+	me.Set(key, item)
+	return item, nil
+}
+
+func (me *lockedCache[K, V]) Get(key K) (Item[V], error) {
+	lock := me.getLock(key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	value, ok := me.entries.Load(key)
+	if !ok {
+		return me.fetchAndStore(key)
+	}
+
+	item := value.(Item[V])
+	if item.Expired(time.Now()) {
+		return me.fetchAndStore(key)
+	}
+
+	return item, nil
+}
+
+func (me *lockedCache[K, V]) Set(key K, item Item[V]) {
+	if me.ttl > 0 {
+		item.Expiration = time.Now().Add(me.ttl)
+	}
+
+	me.entries.Store(key, item)
+}
+
+// NewLockedCache returns an instance of lockedCache
+func NewLockedCache[K comparable, V any](store Adapter[K, V], ttl time.Duration) *lockedCache[K, V] {
+	var entries sync.Map
+	var locks sync.Map
+
+	return &lockedCache[K, V]{store: store, entries: entries, locks: locks, ttl: ttl}
+}
+
+// coalescedCache wraps x/sync's singleflight.Group, keying each in-flight
+// call on a string form of K so concurrent Gets for the same key share one
+// Adapter.Query call.
+type coalescedCache[K comparable, V any] struct {
+	store Adapter[K, V]
+
+	ttl time.Duration
+
+	entries sync.Map
+
+	singleflight singleflight.Group
+}
+
+func (me *coalescedCache[K, V]) fetchAndStore(key K) (Item[V], error) {
+	value, err := me.store.Query(key)
+	if err != nil {
+		return Item[V]{}, err
+	}
+
+	item := Item[V]{Value: value}
+	me.Set(key, item)
+	return item, nil
+}
+
+func (me *coalescedCache[K, V]) Get(key K) (Item[V], error) {
+	value, err, _ := me.singleflight.Do(fmt.Sprint(key), func() (interface{}, error) {
+		item, ok := me.entries.Load(key)
+		if !ok {
+			return me.fetchAndStore(key)
+		}
+
+		it := item.(Item[V])
+		if it.Expired(time.Now()) {
+			return me.fetchAndStore(key)
+		}
+
+		return item, nil
+	})
+
+	if err != nil {
+		return Item[V]{}, err
+	}
+
+	return value.(Item[V]), nil
+}
+
+func (me *coalescedCache[K, V]) Set(key K, item Item[V]) {
+	if me.ttl > 0 {
+		item.Expiration = time.Now().Add(me.ttl)
+	}
+
+	me.entries.Store(key, item)
+}
+
+// NewCoalescedCache returns an instance of coalescedCache
+func NewCoalescedCache[K comparable, V any](store Adapter[K, V], ttl time.Duration) *coalescedCache[K, V] {
+	var entries sync.Map
+	return &coalescedCache[K, V]{store: store, entries: entries, ttl: ttl}
+}
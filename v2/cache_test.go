@@ -0,0 +1,126 @@
+package v2
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKey = "key"
+
+var testElem = "hey oh"
+
+type fakeAdapter struct {
+	value string
+}
+
+func (me *fakeAdapter) Query(key string) (string, error) {
+	return me.value, nil
+}
+
+type slowAdapter struct {
+	value string
+}
+
+func (me *slowAdapter) Query(key string) (string, error) {
+	time.Sleep(time.Microsecond * 2)
+	return me.value, nil
+}
+
+func doCacheTest(t *testing.T, testCache Cache[string, string], concurrentReads int) {
+	i, err := testCache.Get(testKey)
+	assert.Equal(t, testElem, i.Value)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	resultsChan := make(chan Item[string])
+	errChan := make(chan error)
+	successfulGets := 0
+	unsuccessfulGets := 0
+	errorGets := 0
+
+	go func() {
+		for {
+			select {
+			case i, ok := <-resultsChan:
+				if !ok {
+					resultsChan = nil
+					continue
+				}
+				if i.Value == testElem {
+					successfulGets++
+				} else {
+					unsuccessfulGets++
+				}
+			case err, ok := <-errChan:
+				if !ok {
+					errChan = nil
+					continue
+				}
+				if err != nil {
+					errorGets++
+				}
+			}
+
+			if resultsChan == nil && errChan == nil {
+				break
+			}
+		}
+	}()
+
+	for i := 0; i < concurrentReads; i++ {
+		wg.Add(1)
+		go func(wg *sync.WaitGroup) {
+			defer wg.Done()
+			i, err := testCache.Get(testKey)
+			resultsChan <- i
+			errChan <- err
+		}(&wg)
+	}
+
+	wg.Wait()
+	close(resultsChan)
+	close(errChan)
+
+	assert.Equal(t, concurrentReads, successfulGets)
+	assert.Equal(t, 0, unsuccessfulGets)
+	assert.Equal(t, 0, errorGets)
+}
+
+func TestSimpleCache(t *testing.T) {
+	testCache := NewSimpleCache[string, string](&fakeAdapter{testElem}, time.Microsecond)
+	doCacheTest(t, testCache, 0)
+}
+
+func TestLockedCache(t *testing.T) {
+	testCache := NewLockedCache[string, string](&fakeAdapter{testElem}, time.Microsecond)
+	doCacheTest(t, testCache, 0)
+}
+
+func TestCoalescedCache(t *testing.T) {
+	testCache := NewCoalescedCache[string, string](&fakeAdapter{testElem}, time.Microsecond)
+	doCacheTest(t, testCache, 0)
+}
+
+func TestLockedCache_Concurrent(t *testing.T) {
+	testCache := NewLockedCache[string, string](&fakeAdapter{testElem}, time.Microsecond)
+	doCacheTest(t, testCache, 10000)
+}
+
+func TestCoalescedCache_Concurrent(t *testing.T) {
+	testCache := NewCoalescedCache[string, string](&fakeAdapter{testElem}, time.Microsecond)
+	doCacheTest(t, testCache, 10000)
+}
+
+func TestLockedCache_SlowAdapter(t *testing.T) {
+	testCache := NewLockedCache[string, string](&slowAdapter{testElem}, time.Microsecond)
+	doCacheTest(t, testCache, 10000)
+}
+
+func TestCoalescedCache_SlowAdapter(t *testing.T) {
+	testCache := NewCoalescedCache[string, string](&slowAdapter{testElem}, time.Microsecond)
+	doCacheTest(t, testCache, 10000)
+}